@@ -0,0 +1,13 @@
+package main
+
+import (
+	"log"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/app"
+)
+
+func main() {
+	if err := app.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
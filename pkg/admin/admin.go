@@ -0,0 +1,71 @@
+// Package admin serves the operational endpoints (/metrics, /healthz,
+// /readyz) on their own listener, separate from the public API, so
+// they're never gated by API-token auth or the public rate limiter.
+package admin
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/ingest"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/metrics"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/storage"
+)
+
+// readyTimeout bounds how long the DB ping in /readyz may take.
+const readyTimeout = 2 * time.Second
+
+// ingestStaleAfter is how long the ingest pipeline's heartbeat may go
+// quiet before /readyz reports it as stalled.
+const ingestStaleAfter = 15 * time.Second
+
+// Dependencies are what the admin endpoints need to report status.
+type Dependencies struct {
+	Store    storage.LogStore
+	Pipeline *ingest.Pipeline
+	Metrics  *metrics.Metrics
+}
+
+// NewServer builds the admin HTTP server. It's the caller's
+// responsibility to ListenAndServe/Shutdown it independently of the
+// public API server.
+func NewServer(addr string, deps Dependencies) *http.Server {
+	router := gin.New()
+	router.Use(gin.Recovery())
+
+	router.GET("/healthz", healthz)
+	router.GET("/readyz", readyz(deps))
+	router.GET("/metrics", gin.WrapH(deps.Metrics.Handler()))
+
+	return &http.Server{Addr: addr, Handler: router}
+}
+
+// healthz only reports that the process is alive and serving.
+func healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// readyz reports whether the service can actually do its job: the
+// store is reachable and the ingest goroutine is still ticking.
+func readyz(deps Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if pinger, ok := deps.Store.(storage.Pinger); ok {
+			ctx, cancel := context.WithTimeout(c.Request.Context(), readyTimeout)
+			defer cancel()
+			if err := pinger.Ping(ctx); err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"status": "store unreachable", "error": err.Error()})
+				return
+			}
+		}
+
+		if !deps.Pipeline.Alive(ingestStaleAfter) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "ingest pipeline stalled"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "ready"})
+	}
+}
@@ -0,0 +1,96 @@
+package api
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/auth"
+)
+
+const principalContextKey = "principal"
+
+// apiAuthentication resolves the X-API-KEY header to a Principal via
+// authStore and stores it in the gin context, replacing the old
+// shared-secret comparison.
+func apiAuthentication(authStore *auth.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := bearerOrHeaderToken(c)
+		principal, err := authStore.Authenticate(c.Request.Context(), token)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			c.Abort()
+			return
+		}
+		c.Set(principalContextKey, principal)
+		c.Next()
+	}
+}
+
+// requireRole aborts with 403 unless the request's principal has role.
+func requireRole(role auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := c.MustGet(principalContextKey).(*auth.Principal)
+		if principal.Role != role {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient role"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// tokenRateLimitMiddleware replaces the global 5/sec bucket with one
+// sized by the authenticated principal's own rate limit.
+func tokenRateLimitMiddleware(limiter *auth.TokenRateLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal := c.MustGet(principalContextKey).(*auth.Principal)
+		if !limiter.Allow(principal.TokenID, principal.RateLimitPerSec) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// accessLogMiddleware records every request that reaches it to the
+// access_logs table for billing/analytics. NewRouter mounts it after
+// authentication and the rate limiter, so requests rejected by either
+// are not recorded.
+func accessLogMiddleware(authStore *auth.Store, logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		var tokenID *int64
+		if v, ok := c.Get(principalContextKey); ok {
+			id := v.(*auth.Principal).TokenID
+			tokenID = &id
+		}
+
+		entry := auth.AccessLog{
+			Method:    c.Request.Method,
+			Path:      c.Request.URL.Path,
+			Status:    c.Writer.Status(),
+			LatencyMs: time.Since(start).Milliseconds(),
+			TokenID:   tokenID,
+			Bytes:     c.Writer.Size(),
+		}
+		if err := authStore.LogAccess(c.Request.Context(), entry); err != nil {
+			logger.Error("failed to write access log", "error", err)
+		}
+	}
+}
+
+// bearerOrHeaderToken accepts either the legacy X-API-KEY header or a
+// standard "Authorization: Bearer <token>" header.
+func bearerOrHeaderToken(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+	return c.GetHeader("X-API-KEY")
+}
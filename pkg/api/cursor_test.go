@@ -0,0 +1,34 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/storage"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	want := storage.Cursor{
+		Timestamp: time.Date(2026, 7, 26, 12, 0, 1, 0, time.UTC),
+		ID:        42,
+	}
+
+	encoded := encodeCursor(want)
+	got, err := decodeCursor(encoded)
+	if err != nil {
+		t.Fatalf("decodeCursor(%q) returned error: %v", encoded, err)
+	}
+
+	if !got.Timestamp.Equal(want.Timestamp) {
+		t.Errorf("Timestamp = %v, want %v", got.Timestamp, want.Timestamp)
+	}
+	if got.ID != want.ID {
+		t.Errorf("ID = %d, want %d", got.ID, want.ID)
+	}
+}
+
+func TestDecodeCursorRejectsGarbage(t *testing.T) {
+	if _, err := decodeCursor("not valid base64!!"); err == nil {
+		t.Fatal("expected an error decoding a malformed cursor, got nil")
+	}
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/ingest"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/storage"
+)
+
+// maxLogsLimit bounds the page size a caller can request via ?limit=,
+// regardless of what they ask for.
+const maxLogsLimit = 1000
+
+// receiveLogs is the unauthenticated Logplex drain endpoint.
+func receiveLogs(deps *Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userAgent := c.GetHeader("User-Agent")
+		if !strings.Contains(userAgent, "Logplex") && !strings.Contains(userAgent, "logfwd") {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized source"})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Error reading request body"})
+			return
+		}
+
+		frames, err := ingest.ParseLogplexFrames(body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Logplex frame: " + err.Error()})
+			return
+		}
+
+		accepted := 0
+		for _, frame := range frames {
+			msg, err := ingest.ParseRFC5424(frame)
+			if err != nil {
+				deps.Logger.Warn("dropping unparseable syslog message", "error", err)
+				continue
+			}
+
+			err = deps.Ingest.TryEnqueue(storage.LogEntry{
+				Source:         msg.AppName,
+				TimeStamp:      msg.Timestamp,
+				Message:        msg.Message,
+				Priority:       msg.Priority,
+				Facility:       msg.Facility,
+				Severity:       msg.Severity,
+				Hostname:       msg.Hostname,
+				AppName:        msg.AppName,
+				ProcID:         msg.ProcID,
+				MsgID:          msg.MsgID,
+				StructuredData: msg.StructuredData,
+			})
+			if errors.Is(err, ingest.ErrBackpressure) {
+				c.Header("Retry-After", "1")
+				c.JSON(http.StatusTooManyRequests, gin.H{"error": "Ingest buffer full, try again shortly"})
+				return
+			}
+			accepted++
+		}
+
+		if accepted == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "No valid log entries in payload"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "Log entry received", "count": accepted})
+	}
+}
+
+// getLogs is the authenticated log-query endpoint. It supports filtering
+// by source, severity, time range, and substring, plus keyset pagination
+// via ?cursor=, returning a next_cursor the caller can pass back to page
+// through the result set.
+func getLogs(deps *Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := parseLogFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		logs, err := deps.Store.Query(c.Request.Context(), filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch logs"})
+			return
+		}
+
+		resp := gin.H{"logs": logs}
+		if len(logs) == filter.Limit {
+			last := logs[len(logs)-1]
+			resp["next_cursor"] = encodeCursor(storage.Cursor{Timestamp: last.TimeStamp, ID: last.ID})
+		}
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// parseLogFilter builds a storage.LogFilter from getLogs' query string.
+func parseLogFilter(c *gin.Context) (storage.LogFilter, error) {
+	filter := storage.LogFilter{
+		Source:   c.Query("source"),
+		Contains: c.Query("contains"),
+		Limit:    100,
+	}
+
+	if raw := c.Query("severity"); raw != "" {
+		severity, err := strconv.Atoi(raw)
+		if err != nil {
+			return storage.LogFilter{}, fmt.Errorf("invalid severity: %w", err)
+		}
+		filter.Severity = &severity
+	}
+
+	if raw := c.Query("since"); raw != "" {
+		since, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return storage.LogFilter{}, fmt.Errorf("invalid since: %w", err)
+		}
+		filter.Since = since
+	}
+
+	if raw := c.Query("until"); raw != "" {
+		until, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return storage.LogFilter{}, fmt.Errorf("invalid until: %w", err)
+		}
+		filter.Until = until
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			return storage.LogFilter{}, fmt.Errorf("invalid limit: %w", err)
+		}
+		filter.Limit = limit
+	}
+	if filter.Limit <= 0 || filter.Limit > maxLogsLimit {
+		filter.Limit = maxLogsLimit
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		cursor, err := decodeCursor(raw)
+		if err != nil {
+			return storage.LogFilter{}, fmt.Errorf("invalid cursor: %w", err)
+		}
+		filter.Cursor = &cursor
+	}
+
+	return filter, nil
+}
+
+// encodeCursor and decodeCursor turn a storage.Cursor into an opaque
+// string safe to hand back to callers, so pagination doesn't leak the
+// (timestamp, id) encoding as part of the API's contract.
+func encodeCursor(cursor storage.Cursor) string {
+	raw := fmt.Sprintf("%d:%d", cursor.Timestamp.UnixNano(), cursor.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(encoded string) (storage.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return storage.Cursor{}, err
+	}
+
+	var unixNano, id int64
+	if _, err := fmt.Sscanf(string(raw), "%d:%d", &unixNano, &id); err != nil {
+		return storage.Cursor{}, fmt.Errorf("malformed cursor")
+	}
+	return storage.Cursor{Timestamp: time.Unix(0, unixNano), ID: id}, nil
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const requestIDHeader = "X-Request-ID"
+const requestIDContextKey = "request_id"
+
+// requestIDMiddleware assigns a request ID (reusing one supplied by the
+// caller, if present) and stores it in the gin context so handlers and
+// logs can correlate a single request.
+func requestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(requestIDHeader)
+		if id == "" {
+			id = newRequestID()
+		}
+		c.Set(requestIDContextKey, id)
+		c.Writer.Header().Set(requestIDHeader, id)
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// requestLoggingMiddleware emits one structured log line per request,
+// replacing gin's default text access log.
+func requestLoggingMiddleware(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		logger.Info("request",
+			"request_id", c.GetString(requestIDContextKey),
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	}
+}
@@ -0,0 +1,62 @@
+// Package api owns gin router setup: route registration, middleware,
+// and the handlers that used to close over main.go's package-level
+// globals. Everything a handler needs now comes from Dependencies.
+package api
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/auth"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/ingest"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/metrics"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/storage"
+)
+
+// Dependencies are the services handlers need, wired up once at
+// startup instead of being read from package-level globals.
+type Dependencies struct {
+	Store   storage.LogStore
+	Auth    *auth.Store
+	Ingest  *ingest.Pipeline
+	Limiter *auth.TokenRateLimiter
+	Logger  *slog.Logger
+	Metrics *metrics.Metrics
+}
+
+// NewRouter builds the gin engine, mounting the same handlers under
+// the unversioned legacy paths and under each of /v1 and /v2.
+func NewRouter(deps *Dependencies) *gin.Engine {
+	router := gin.New()
+	router.Use(gin.Recovery(), requestIDMiddleware(), requestLoggingMiddleware(deps.Logger), deps.Metrics.GinMiddleware())
+
+	router.POST("/logs", receiveLogs(deps))
+
+	authorized := router.Group("/")
+	authorized.Use(apiAuthentication(deps.Auth), tokenRateLimitMiddleware(deps.Limiter), accessLogMiddleware(deps.Auth, deps.Logger))
+	registerAuthorizedRoutes(authorized, deps)
+
+	for _, version := range []string{"/v1", "/v2"} {
+		versioned := router.Group(version)
+		versioned.POST("/logs", receiveLogs(deps))
+
+		versionedAuthorized := versioned.Group("/")
+		versionedAuthorized.Use(apiAuthentication(deps.Auth), tokenRateLimitMiddleware(deps.Limiter), accessLogMiddleware(deps.Auth, deps.Logger))
+		registerAuthorizedRoutes(versionedAuthorized, deps)
+	}
+
+	return router
+}
+
+func registerAuthorizedRoutes(group *gin.RouterGroup, deps *Dependencies) {
+	group.GET("/logs", getLogs(deps))
+	group.GET("/logs/stream", streamLogs(deps))
+
+	admin := group.Group("/")
+	admin.Use(requireRole(auth.RoleAdmin))
+	admin.POST("/tokens", createToken(deps.Auth))
+	admin.GET("/tokens", listTokens(deps.Auth))
+	admin.DELETE("/tokens/:id", revokeToken(deps.Auth))
+	admin.GET("/access-logs", listAccessLogs(deps.Auth))
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/ingest"
+)
+
+// streamLogs is the authenticated live-tail endpoint. It holds the
+// connection open and pushes newly-ingested entries matching the
+// source/severity/contains query params as Server-Sent Events, until the
+// client disconnects.
+func streamLogs(deps *Dependencies) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := ingest.StreamFilter{
+			Source:   c.Query("source"),
+			Contains: c.Query("contains"),
+		}
+		if raw := c.Query("severity"); raw != "" {
+			severity, err := strconv.Atoi(raw)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid severity"})
+				return
+			}
+			filter.Severity = &severity
+		}
+
+		entries, unsubscribe := deps.Ingest.Subscribe(filter)
+		defer unsubscribe()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+
+		c.Stream(func(w io.Writer) bool {
+			select {
+			case entry, ok := <-entries:
+				if !ok {
+					return false
+				}
+				c.SSEvent("log", entry)
+				return true
+			case <-c.Request.Context().Done():
+				return false
+			}
+		})
+	}
+}
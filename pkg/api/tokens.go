@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/auth"
+)
+
+type createTokenRequest struct {
+	Role            auth.Role  `json:"role" binding:"required"`
+	RateLimitPerSec int        `json:"rate_limit_per_sec"`
+	ExpiresAt       *time.Time `json:"expires_at"`
+}
+
+// createToken handles POST /tokens (admin only).
+func createToken(authStore *auth.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req createTokenRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"})
+			return
+		}
+
+		raw, token, err := authStore.CreateToken(c.Request.Context(), req.Role, req.RateLimitPerSec, req.ExpiresAt)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create token"})
+			return
+		}
+
+		c.JSON(http.StatusCreated, gin.H{"token": raw, "id": token.ID, "role": token.Role})
+	}
+}
+
+// revokeToken handles DELETE /tokens/:id (admin only).
+func revokeToken(authStore *auth.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token id"})
+			return
+		}
+
+		if err := authStore.RevokeToken(c.Request.Context(), id); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "revoked"})
+	}
+}
+
+// listTokens handles GET /tokens (admin only).
+func listTokens(authStore *auth.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokens, err := authStore.ListTokens(c.Request.Context())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list tokens"})
+			return
+		}
+		c.JSON(http.StatusOK, tokens)
+	}
+}
+
+// listAccessLogs handles GET /access-logs (admin only).
+func listAccessLogs(authStore *auth.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		logs, err := authStore.ListAccessLogs(c.Request.Context(), 100)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to list access logs"})
+			return
+		}
+		c.JSON(http.StatusOK, logs)
+	}
+}
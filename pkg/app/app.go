@@ -0,0 +1,139 @@
+// Package app wires the service's dependencies together and runs it.
+// cmd/logdrain/main.go is a thin wrapper around Run.
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/admin"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/api"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/auth"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/config"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/ingest"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/metrics"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/storage"
+)
+
+// channelDepthPollInterval governs how often the ingest channel depth
+// gauge is refreshed from the pipeline's own counters.
+const channelDepthPollInterval = 5 * time.Second
+
+// Run loads configuration, opens the storage and auth backends, starts
+// the ingest pipeline, and serves HTTP until it receives SIGTERM/SIGINT,
+// at which point it drains in-flight log entries before exiting.
+func Run() error {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	cfg := config.Load(os.Getenv)
+
+	store, err := storage.Open(cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("app: opening storage: %w", err)
+	}
+	defer store.Close()
+
+	authStore, err := auth.Open(cfg.DatabaseURL)
+	if err != nil {
+		return fmt.Errorf("app: opening auth store: %w", err)
+	}
+	defer authStore.Close()
+
+	pipeline, err := ingest.New(store, cfg.Ingest, logger)
+	if err != nil {
+		return fmt.Errorf("app: starting ingest pipeline: %w", err)
+	}
+
+	m := metrics.New()
+	pipeline.SetMetrics(m)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var pipelineDone sync.WaitGroup
+	pipelineDone.Add(1)
+	go func() {
+		defer pipelineDone.Done()
+		pipeline.Run(ctx)
+	}()
+
+	go pollChannelDepth(ctx, pipeline, m)
+
+	if cfg.Storage.Archive.Enabled {
+		compactor, err := storage.NewCompactor(store, cfg.Storage.Archive.OlderThan, cfg.Storage.Archive.CompactInterval)
+		if err != nil {
+			return fmt.Errorf("app: starting compactor: %w", err)
+		}
+		go compactor.Run(ctx)
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	router := api.NewRouter(&api.Dependencies{
+		Store:   store,
+		Auth:    authStore,
+		Ingest:  pipeline,
+		Limiter: auth.NewTokenRateLimiter(),
+		Logger:  logger,
+		Metrics: m,
+	})
+	server := &http.Server{Addr: ":" + cfg.Port, Handler: router}
+
+	adminServer := admin.NewServer(":"+cfg.AdminPort, admin.Dependencies{
+		Store:    store,
+		Pipeline: pipeline,
+		Metrics:  m,
+	})
+
+	serveErr := make(chan error, 2)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serveErr <- fmt.Errorf("serving http: %w", err)
+		}
+	}()
+	go func() {
+		if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			serveErr <- fmt.Errorf("serving admin http: %w", err)
+		}
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("app: %w", err)
+	case <-ctx.Done():
+		logger.Info("shutting down")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("app: shutting down http server: %w", err)
+	}
+	if err := adminServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("app: shutting down admin http server: %w", err)
+	}
+
+	pipelineDone.Wait()
+	return nil
+}
+
+func pollChannelDepth(ctx context.Context, pipeline *ingest.Pipeline, m *metrics.Metrics) {
+	ticker := time.NewTicker(channelDepthPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.SetIngestChannelDepth(pipeline.Stats().ChannelDepth)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
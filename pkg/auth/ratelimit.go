@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/ratelimit"
+)
+
+// TokenRateLimiter enforces a distinct rate limit per token, sized by
+// each token's RateLimitPerSec, instead of one global bucket.
+type TokenRateLimiter struct {
+	mu      sync.Mutex
+	buckets map[int64]*ratelimit.Bucket
+}
+
+func NewTokenRateLimiter() *TokenRateLimiter {
+	return &TokenRateLimiter{buckets: make(map[int64]*ratelimit.Bucket)}
+}
+
+// Allow reports whether a request for tokenID may proceed under its
+// per-second rate limit, creating that token's bucket on first use.
+func (l *TokenRateLimiter) Allow(tokenID int64, ratePerSec int) bool {
+	if ratePerSec <= 0 {
+		ratePerSec = 5
+	}
+
+	l.mu.Lock()
+	bucket, ok := l.buckets[tokenID]
+	if !ok {
+		bucket = ratelimit.NewBucket(1*time.Second/time.Duration(ratePerSec), int64(ratePerSec))
+		l.buckets[tokenID] = bucket
+	}
+	l.mu.Unlock()
+
+	return bucket.TakeAvailable(1) == 1
+}
@@ -0,0 +1,28 @@
+package auth
+
+import "testing"
+
+func TestTokenRateLimiterPerToken(t *testing.T) {
+	limiter := NewTokenRateLimiter()
+
+	for i := 0; i < 3; i++ {
+		if !limiter.Allow(1, 3) {
+			t.Fatalf("request %d for token 1 should be allowed within its burst", i)
+		}
+	}
+	if limiter.Allow(1, 3) {
+		t.Fatal("token 1 should be rate-limited after exhausting its burst")
+	}
+
+	// A different token must not be affected by token 1's bucket.
+	if !limiter.Allow(2, 3) {
+		t.Fatal("token 2 should have its own independent bucket")
+	}
+}
+
+func TestTokenRateLimiterDefaultRate(t *testing.T) {
+	limiter := NewTokenRateLimiter()
+	if !limiter.Allow(1, 0) {
+		t.Fatal("a non-positive rate should fall back to the default and still allow the first request")
+	}
+}
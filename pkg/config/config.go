@@ -0,0 +1,94 @@
+// Package config loads the service's runtime configuration from the
+// environment into a typed Config, so the rest of the app never calls
+// os.Getenv directly.
+package config
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/ingest"
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/storage"
+)
+
+// Config is the fully resolved configuration for one run of the service.
+type Config struct {
+	Port        string
+	AdminPort   string
+	DatabaseURL string
+	Storage     storage.Config
+	Ingest      ingest.Config
+}
+
+// Load reads Config from the environment, applying the same defaults
+// the service has always used.
+func Load(getenv func(string) string) Config {
+	cfg := Config{
+		Port:        orDefault(getenv("PORT"), "8080"),
+		AdminPort:   orDefault(getenv("ADMIN_PORT"), "9090"),
+		DatabaseURL: getenv("DATABASE_URL"),
+	}
+
+	cfg.Ingest = ingest.Config{
+		ChannelSize:    intOrDefault(getenv("INGEST_CHANNEL_SIZE"), 100),
+		FlushInterval:  durationOrDefault(getenv("INGEST_FLUSH_INTERVAL"), 5*time.Minute),
+		MaxBatchSize:   intOrDefault(getenv("INGEST_MAX_BATCH_SIZE"), 500),
+		WALDir:         getenv("INGEST_WAL_DIR"),
+		MaxRetries:     intOrDefault(getenv("INGEST_MAX_RETRIES"), 3),
+		RetryBackoff:   durationOrDefault(getenv("INGEST_RETRY_BACKOFF"), 500*time.Millisecond),
+		DeadLetterPath: getenv("INGEST_DEAD_LETTER_PATH"),
+	}
+
+	cfg.Storage = storage.Config{
+		Backend:     storage.Backend(getenv("STORAGE_BACKEND")),
+		DatabaseURL: cfg.DatabaseURL,
+		SQLitePath:  getenv("SQLITE_PATH"),
+	}
+
+	if bucket := getenv("ARCHIVE_BUCKET"); bucket != "" {
+		olderThan := 30 * 24 * time.Hour
+		if raw := getenv("ARCHIVE_OLDER_THAN_HOURS"); raw != "" {
+			if hours, err := strconv.Atoi(raw); err == nil {
+				olderThan = time.Duration(hours) * time.Hour
+			}
+		}
+		cfg.Storage.Archive = storage.ArchiveConfig{
+			Enabled:         true,
+			Bucket:          bucket,
+			Prefix:          getenv("ARCHIVE_PREFIX"),
+			OlderThan:       olderThan,
+			CompactInterval: durationOrDefault(getenv("ARCHIVE_COMPACT_INTERVAL"), time.Hour),
+		}
+	}
+
+	return cfg
+}
+
+func orDefault(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+func intOrDefault(v string, def int) int {
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+func durationOrDefault(v string, def time.Duration) time.Duration {
+	if v == "" {
+		return def
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return def
+	}
+	return d
+}
@@ -0,0 +1,32 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/storage"
+)
+
+// appendDeadLetter records entries that repeatedly failed to flush so
+// they aren't silently lost; it's an append-only NDJSON file meant for
+// manual inspection/replay, not automatic retry.
+func appendDeadLetter(path string, entries []storage.LogEntry) error {
+	if path == "" {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("ingest: opening dead-letter file %s: %w", path, err)
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("ingest: writing dead-letter entry: %w", err)
+		}
+	}
+	return nil
+}
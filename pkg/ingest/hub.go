@@ -0,0 +1,95 @@
+package ingest
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/storage"
+)
+
+// StreamFilter narrows which freshly-ingested entries a subscriber
+// receives. It mirrors the subset of storage.LogFilter that makes sense
+// for a live tail; time range and pagination only make sense against a
+// finished query, not a stream of entries as they arrive.
+type StreamFilter struct {
+	Source   string
+	Severity *int
+	Contains string
+}
+
+func (f StreamFilter) matches(e storage.LogEntry) bool {
+	if f.Source != "" && e.Source != f.Source {
+		return false
+	}
+	if f.Severity != nil && e.Severity != *f.Severity {
+		return false
+	}
+	if f.Contains != "" && !strings.Contains(strings.ToLower(e.Message), strings.ToLower(f.Contains)) {
+		return false
+	}
+	return true
+}
+
+// subscriberBufferSize bounds how far a subscriber can lag before its
+// entries start being dropped. Streaming is best-effort: a slow reader
+// must never be allowed to block ingestion.
+const subscriberBufferSize = 64
+
+type subscriber struct {
+	ch     chan storage.LogEntry
+	filter StreamFilter
+}
+
+// hub fans out entries accepted by TryEnqueue to any live /logs/stream
+// subscribers.
+type hub struct {
+	mu   sync.RWMutex
+	subs map[int64]*subscriber
+	next int64
+}
+
+func newHub() *hub {
+	return &hub{subs: make(map[int64]*subscriber)}
+}
+
+// subscribe registers a new subscriber matching filter and returns its
+// channel along with an unsubscribe func the caller must invoke once
+// (typically via defer) to release it.
+func (h *hub) subscribe(filter StreamFilter) (<-chan storage.LogEntry, func()) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	id := h.next
+	h.next++
+	sub := &subscriber{ch: make(chan storage.LogEntry, subscriberBufferSize), filter: filter}
+	h.subs[id] = sub
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+			delete(h.subs, id)
+			close(sub.ch)
+		})
+	}
+	return sub.ch, unsubscribe
+}
+
+// publish fans entry out to every subscriber whose filter matches it.
+// A subscriber whose buffer is full has the entry dropped rather than
+// blocking the caller.
+func (h *hub) publish(e storage.LogEntry) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, sub := range h.subs {
+		if !sub.filter.matches(e) {
+			continue
+		}
+		select {
+		case sub.ch <- e:
+		default:
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package ingest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Logplex frames each syslog message with an octet count so messages
+// containing embedded newlines can be split safely:
+//
+//	<len> <SYSLOG-MSG><len> <SYSLOG-MSG>...
+//
+// See https://tools.ietf.org/html/rfc6587#section-3.4.1 (octet counting).
+func ParseLogplexFrames(body []byte) ([]string, error) {
+	var frames []string
+	r := bufio.NewReader(strings.NewReader(string(body)))
+
+	for {
+		lengthStr, err := r.ReadString(' ')
+		if err != nil {
+			if lengthStr == "" {
+				break
+			}
+			return nil, fmt.Errorf("reading frame length: %w", err)
+		}
+		lengthStr = strings.TrimSpace(lengthStr)
+		if lengthStr == "" {
+			break
+		}
+
+		length, err := strconv.Atoi(lengthStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid frame length %q: %w", lengthStr, err)
+		}
+
+		frame := make([]byte, length)
+		if _, err := io.ReadFull(r, frame); err != nil {
+			return nil, fmt.Errorf("reading frame body of length %d: %w", length, err)
+		}
+		frames = append(frames, string(frame))
+	}
+
+	if len(frames) == 0 {
+		return nil, fmt.Errorf("no frames found in body")
+	}
+	return frames, nil
+}
+
+// SyslogMessage is the decoded form of a single RFC5424 message.
+//
+// <PRI>VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA MSG
+type SyslogMessage struct {
+	Priority       int
+	Facility       int
+	Severity       int
+	Version        int
+	Timestamp      time.Time
+	Hostname       string
+	AppName        string
+	ProcID         string
+	MsgID          string
+	StructuredData string
+	Message        string
+}
+
+// ParseRFC5424 decodes a single syslog message as emitted by Heroku's
+// Logplex (RFC5424, https://tools.ietf.org/html/rfc5424#section-6).
+func ParseRFC5424(raw string) (*SyslogMessage, error) {
+	raw = strings.TrimRight(raw, "\n")
+
+	if len(raw) == 0 || raw[0] != '<' {
+		return nil, fmt.Errorf("missing PRI in message: %q", raw)
+	}
+	end := strings.IndexByte(raw, '>')
+	if end < 0 {
+		return nil, fmt.Errorf("unterminated PRI in message: %q", raw)
+	}
+
+	pri, err := strconv.Atoi(raw[1:end])
+	if err != nil {
+		return nil, fmt.Errorf("invalid PRI %q: %w", raw[1:end], err)
+	}
+
+	// Only VERSION/TIMESTAMP/HOSTNAME/APP-NAME/PROCID are fixed,
+	// space-free tokens; STRUCTURED-DATA and MSG can both contain
+	// internal spaces (SD elements like "[id@32473 iut=\"3\"]"), so they
+	// can't be carved out with a fixed SplitN count. Split off exactly
+	// those five fields, peel MSGID off the front of what's left, then
+	// hand the untouched remainder to splitStructuredData, which already
+	// knows how to find the end of STRUCTURED-DATA by bracket depth.
+	rest := raw[end+1:]
+	fields := strings.SplitN(rest, " ", 6)
+	if len(fields) < 6 {
+		return nil, fmt.Errorf("expected 6 header fields, got %d: %q", len(fields), rest)
+	}
+
+	version, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid VERSION %q: %w", fields[0], err)
+	}
+
+	msgID, tail := splitMsgID(fields[5])
+
+	msg := &SyslogMessage{
+		Priority: pri,
+		Facility: pri / 8,
+		Severity: pri % 8,
+		Version:  version,
+		Hostname: nilDashToEmpty(fields[2]),
+		AppName:  nilDashToEmpty(fields[3]),
+		ProcID:   nilDashToEmpty(fields[4]),
+		MsgID:    msgID,
+	}
+
+	if fields[1] != "-" {
+		ts, err := time.Parse(time.RFC3339Nano, fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid TIMESTAMP %q: %w", fields[1], err)
+		}
+		msg.Timestamp = ts
+	} else {
+		msg.Timestamp = time.Now().UTC()
+	}
+
+	sd, body := splitStructuredData(tail)
+	msg.StructuredData = sd
+	msg.Message = body
+
+	return msg, nil
+}
+
+// splitMsgID peels the MSGID token off the front of tail (everything
+// after PROCID), returning it and the untouched remainder starting at
+// STRUCTURED-DATA. MSGID is a plain token and can never start with "[",
+// the only character that opens STRUCTURED-DATA, so a tail that already
+// starts there means MSGID was omitted rather than sent as "-".
+func splitMsgID(tail string) (msgID, remainder string) {
+	if strings.HasPrefix(tail, "[") {
+		return "", tail
+	}
+	parts := strings.SplitN(tail, " ", 2)
+	if len(parts) == 2 {
+		return nilDashToEmpty(parts[0]), parts[1]
+	}
+	return nilDashToEmpty(parts[0]), ""
+}
+
+// nilDashToEmpty maps the RFC5424 NILVALUE ("-") to an empty string.
+func nilDashToEmpty(s string) string {
+	if s == "-" {
+		return ""
+	}
+	return s
+}
+
+// splitStructuredData separates the leading STRUCTURED-DATA element(s)
+// (either "-" or one or more "[...]" blocks) from the trailing MSG.
+func splitStructuredData(s string) (sd string, msg string) {
+	if strings.HasPrefix(s, "-") {
+		return "", strings.TrimPrefix(s, "- ")
+	}
+	if !strings.HasPrefix(s, "[") {
+		return "", s
+	}
+
+	depth := 0
+	for i, r := range s {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				// Structured data ends here; skip the single space
+				// separating it from MSG, if present.
+				rest := s[i+1:]
+				return s[:i+1], strings.TrimPrefix(rest, " ")
+			}
+		}
+	}
+	return s, ""
+}
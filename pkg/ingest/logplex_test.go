@@ -0,0 +1,101 @@
+package ingest
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseRFC5424(t *testing.T) {
+	tests := []struct {
+		name           string
+		raw            string
+		wantAppName    string
+		wantProcID     string
+		wantMsgID      string
+		wantStructData string
+		wantMessage    string
+	}{
+		{
+			name:           "structured data with internal spaces",
+			raw:            `<158>1 2026-07-26T12:00:01.0+00:00 myapp web.2 - [exampleSDID@32473 iut="3"] second message`,
+			wantAppName:    "web.2",
+			wantProcID:     "",
+			wantMsgID:      "",
+			wantStructData: `[exampleSDID@32473 iut="3"]`,
+			wantMessage:    "second message",
+		},
+		{
+			name:           "all nil header fields, no structured data",
+			raw:            `<13>1 - - - - - - just a message`,
+			wantAppName:    "",
+			wantProcID:     "",
+			wantMsgID:      "",
+			wantStructData: "",
+			wantMessage:    "just a message",
+		},
+		{
+			name:           "explicit msgid before structured data",
+			raw:            `<13>1 2026-07-26T12:00:01.0+00:00 myapp web.1 123 ID47 [exampleSDID@32473 iut="3"] hello`,
+			wantAppName:    "web.1",
+			wantProcID:     "123",
+			wantMsgID:      "ID47",
+			wantStructData: `[exampleSDID@32473 iut="3"]`,
+			wantMessage:    "hello",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := ParseRFC5424(tt.raw)
+			if err != nil {
+				t.Fatalf("ParseRFC5424(%q) returned error: %v", tt.raw, err)
+			}
+			if msg.AppName != tt.wantAppName {
+				t.Errorf("AppName = %q, want %q", msg.AppName, tt.wantAppName)
+			}
+			if msg.ProcID != tt.wantProcID {
+				t.Errorf("ProcID = %q, want %q", msg.ProcID, tt.wantProcID)
+			}
+			if msg.MsgID != tt.wantMsgID {
+				t.Errorf("MsgID = %q, want %q", msg.MsgID, tt.wantMsgID)
+			}
+			if msg.StructuredData != tt.wantStructData {
+				t.Errorf("StructuredData = %q, want %q", msg.StructuredData, tt.wantStructData)
+			}
+			if msg.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", msg.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestParseRFC5424MissingPRI(t *testing.T) {
+	if _, err := ParseRFC5424("1 - - - - - - msg"); err == nil {
+		t.Fatal("expected an error for a message missing PRI, got nil")
+	}
+}
+
+func TestParseLogplexFrames(t *testing.T) {
+	want := []string{
+		`<13>1 - - - - - - first message`,
+		`<13>1 - - - - - - second, with embedded spaces`,
+	}
+
+	var body string
+	for _, frame := range want {
+		body += fmt.Sprintf("%d %s", len(frame), frame)
+	}
+
+	frames, err := ParseLogplexFrames([]byte(body))
+	if err != nil {
+		t.Fatalf("ParseLogplexFrames returned error: %v", err)
+	}
+	if len(frames) != len(want) {
+		t.Fatalf("got %d frames, want %d: %v", len(frames), len(want), frames)
+	}
+	for i := range want {
+		if frames[i] != want[i] {
+			t.Errorf("frame[%d] = %q, want %q", i, frames[i], want[i])
+		}
+	}
+}
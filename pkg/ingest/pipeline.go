@@ -0,0 +1,355 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/storage"
+)
+
+// ErrBackpressure is returned by TryEnqueue when the ingest channel is
+// too full to safely accept more entries; callers should surface this
+// as a 429 with a Retry-After hint rather than blocking the caller.
+var ErrBackpressure = errors.New("ingest: channel over capacity")
+
+// MetricsRecorder receives flush observations for the Prometheus
+// endpoint. It's satisfied by *metrics.Metrics; ingest doesn't import
+// the metrics package directly so it stays free of the prometheus
+// dependency.
+type MetricsRecorder interface {
+	ObserveFlush(duration time.Duration, batchSize int, err error)
+}
+
+type noopMetricsRecorder struct{}
+
+func (noopMetricsRecorder) ObserveFlush(time.Duration, int, error) {}
+
+// backpressureThreshold is the fraction of channel capacity in use
+// above which TryEnqueue starts rejecting new entries.
+const backpressureThreshold = 0.8
+
+// Config controls buffering, flushing, and durability for a Pipeline.
+type Config struct {
+	ChannelSize   int
+	FlushInterval time.Duration
+	MaxBatchSize  int
+
+	// WALDir, if non-empty, enables a write-ahead log: every accepted
+	// entry is appended and fsync'd to disk before TryEnqueue returns,
+	// and replayed into the store on startup if the process crashed
+	// before flushing. A failed WAL append is logged and the entry is
+	// still accepted.
+	WALDir string
+
+	// MaxRetries and RetryBackoff govern how a failed store.Append is
+	// retried before falling through to the dead-letter file.
+	MaxRetries     int
+	RetryBackoff   time.Duration
+	DeadLetterPath string
+}
+
+// Stats is a snapshot of the ingest pipeline's counters. Of these, only
+// ChannelDepth is exported to Prometheus (as the ingest channel depth
+// gauge); the metrics package has no collectors for the other fields.
+type Stats struct {
+	Accepted    int64
+	Dropped     int64
+	Flushed     int64
+	WALReplayed int64
+	DLQ         int64
+
+	ChannelDepth    int
+	ChannelCapacity int
+}
+
+// Pipeline buffers ingested LogEntry values in memory and periodically
+// flushes them to a storage.LogStore.
+type Pipeline struct {
+	store  storage.LogStore
+	logger *slog.Logger
+	cfg    Config
+
+	channel chan storage.LogEntry
+
+	mu  sync.Mutex
+	buf []storage.LogEntry
+
+	wal     *walSegment
+	metrics MetricsRecorder
+	hub     *hub
+
+	accepted, dropped, flushed, walReplayed, dlq int64
+	lastHeartbeat                                int64 // unix nanos, atomic
+}
+
+// New builds a Pipeline per cfg, replaying any WAL segments left behind
+// by a previous crash before accepting new entries.
+func New(store storage.LogStore, cfg Config, logger *slog.Logger) (*Pipeline, error) {
+	if cfg.ChannelSize <= 0 {
+		cfg.ChannelSize = 100
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Minute
+	}
+	if cfg.MaxBatchSize <= 0 {
+		cfg.MaxBatchSize = 500
+	}
+	if cfg.MaxRetries <= 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.RetryBackoff <= 0 {
+		cfg.RetryBackoff = 500 * time.Millisecond
+	}
+
+	p := &Pipeline{
+		store:   store,
+		logger:  logger,
+		cfg:     cfg,
+		channel: make(chan storage.LogEntry, cfg.ChannelSize),
+		metrics: noopMetricsRecorder{},
+		hub:     newHub(),
+	}
+
+	if cfg.WALDir != "" {
+		replayed, err := replayWAL(cfg.WALDir)
+		if err != nil {
+			return nil, err
+		}
+		if len(replayed) > 0 {
+			if err := p.store.Append(context.Background(), replayed); err != nil {
+				return nil, err
+			}
+			p.walReplayed = int64(len(replayed))
+			logger.Info("replayed wal segments", "count", len(replayed))
+		}
+
+		wal, err := newWALSegment(cfg.WALDir)
+		if err != nil {
+			return nil, err
+		}
+		p.wal = wal
+	}
+
+	return p, nil
+}
+
+// SetMetrics attaches a MetricsRecorder that future flushes report to.
+func (p *Pipeline) SetMetrics(m MetricsRecorder) {
+	p.metrics = m
+}
+
+// TryEnqueue buffers entry for the next flush, rejecting it with
+// ErrBackpressure if the channel is over backpressureThreshold full
+// rather than blocking the caller.
+//
+// The channel send and WAL append happen under p.mu, the same lock
+// flush uses to drain the channel and rotate the WAL segment. That
+// keeps every entry unambiguously on one side of a rotation: either it
+// lands in p.buf before the old segment is retired (so it's part of
+// the batch that retires it) or it's appended to the new segment after
+// (so it's retired by the next flush instead). Without that shared
+// lock, an entry could be WAL'd to a segment that gets deleted before
+// the entry ever reaches p.buf.
+//
+// The send is attempted before the WAL append, and the WAL append is
+// skipped entirely if the send fails: the initial capacity check above
+// is advisory (read outside the lock, so it can be stale by the time
+// we acquire it), and the channel can still be full once we get here.
+// Writing to the WAL before knowing the send succeeded would durably
+// record an entry that never made it into p.buf, which flush would
+// then discard when it retires the segment.
+func (p *Pipeline) TryEnqueue(entry storage.LogEntry) error {
+	if float64(len(p.channel)) >= backpressureThreshold*float64(cap(p.channel)) {
+		atomic.AddInt64(&p.dropped, 1)
+		return ErrBackpressure
+	}
+
+	p.mu.Lock()
+	select {
+	case p.channel <- entry:
+	default:
+		p.mu.Unlock()
+		atomic.AddInt64(&p.dropped, 1)
+		return ErrBackpressure
+	}
+
+	if p.wal != nil {
+		if err := p.wal.Append(entry); err != nil {
+			p.logger.Error("wal append failed", "error", err)
+		}
+	}
+	p.mu.Unlock()
+
+	atomic.AddInt64(&p.accepted, 1)
+	p.hub.publish(entry)
+	return nil
+}
+
+// Subscribe registers a live tail matching filter, returning a channel
+// of freshly-ingested entries and an unsubscribe func the caller must
+// invoke (typically via defer) once it's done reading.
+func (p *Pipeline) Subscribe(filter StreamFilter) (<-chan storage.LogEntry, func()) {
+	return p.hub.subscribe(filter)
+}
+
+// Stats returns a snapshot of the pipeline's counters.
+func (p *Pipeline) Stats() Stats {
+	return Stats{
+		Accepted:        atomic.LoadInt64(&p.accepted),
+		Dropped:         atomic.LoadInt64(&p.dropped),
+		Flushed:         atomic.LoadInt64(&p.flushed),
+		WALReplayed:     atomic.LoadInt64(&p.walReplayed),
+		DLQ:             atomic.LoadInt64(&p.dlq),
+		ChannelDepth:    len(p.channel),
+		ChannelCapacity: cap(p.channel),
+	}
+}
+
+// Run drains the ingest channel, flushing whenever the ticker fires or
+// the buffer reaches MaxBatchSize (whichever comes first), until ctx is
+// cancelled, at which point it flushes whatever remains before returning.
+func (p *Pipeline) Run(ctx context.Context) {
+	timer := time.NewTimer(p.cfg.FlushInterval)
+	defer timer.Stop()
+
+	heartbeat := time.NewTicker(5 * time.Second)
+	defer heartbeat.Stop()
+	p.beat()
+
+	for {
+		select {
+		case entry := <-p.channel:
+			p.mu.Lock()
+			p.buf = append(p.buf, entry)
+			full := len(p.buf) >= p.cfg.MaxBatchSize
+			p.mu.Unlock()
+
+			if full {
+				p.flush(ctx)
+				resetTimer(timer, p.cfg.FlushInterval)
+			}
+
+		case <-timer.C:
+			p.flush(ctx)
+			timer.Reset(p.cfg.FlushInterval)
+
+		case <-heartbeat.C:
+			p.beat()
+
+		case <-ctx.Done():
+			p.drainChannel()
+			p.flush(context.Background())
+			return
+		}
+	}
+}
+
+func (p *Pipeline) beat() {
+	atomic.StoreInt64(&p.lastHeartbeat, time.Now().UnixNano())
+}
+
+// Alive reports whether Run's goroutine has ticked within maxAge,
+// for use by the /readyz probe.
+func (p *Pipeline) Alive(maxAge time.Duration) bool {
+	last := atomic.LoadInt64(&p.lastHeartbeat)
+	if last == 0 {
+		return false
+	}
+	return time.Since(time.Unix(0, last)) <= maxAge
+}
+
+func resetTimer(t *time.Timer, d time.Duration) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	t.Reset(d)
+}
+
+// drainChannelLocked moves every entry currently sitting in the ingest
+// channel into buf. Callers must hold p.mu.
+func (p *Pipeline) drainChannelLocked() {
+	for {
+		select {
+		case entry := <-p.channel:
+			p.buf = append(p.buf, entry)
+		default:
+			return
+		}
+	}
+}
+
+func (p *Pipeline) drainChannel() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.drainChannelLocked()
+}
+
+func (p *Pipeline) flush(ctx context.Context) {
+	p.mu.Lock()
+	// Drain the channel before snapshotting buf so any entry a
+	// concurrent TryEnqueue already WAL'd to the current segment (but
+	// hadn't yet handed to Run's loop) is captured in this batch rather
+	// than left behind when the segment below is retired.
+	p.drainChannelLocked()
+	logs := p.buf
+	p.buf = nil
+
+	// Swap in a fresh WAL segment for whatever TryEnqueue writes next,
+	// so the old segment can be safely retired once this batch (which
+	// is everything it could possibly hold) is durably flushed.
+	var oldWAL *walSegment
+	if len(logs) > 0 && p.wal != nil {
+		newWAL, err := newWALSegment(p.cfg.WALDir)
+		if err != nil {
+			p.logger.Error("wal rotate failed", "error", err)
+		} else {
+			oldWAL = p.wal
+			p.wal = newWAL
+		}
+	}
+	p.mu.Unlock()
+
+	if len(logs) == 0 {
+		return
+	}
+
+	start := time.Now()
+	err := p.appendWithRetry(ctx, logs)
+	p.metrics.ObserveFlush(time.Since(start), len(logs), err)
+
+	if err != nil {
+		p.logger.Error("batch save failed after retries, writing to dead-letter file", "error", err, "batch_size", len(logs))
+		if dlqErr := appendDeadLetter(p.cfg.DeadLetterPath, logs); dlqErr != nil {
+			p.logger.Error("dead-letter write failed", "error", dlqErr)
+		}
+		atomic.AddInt64(&p.dlq, int64(len(logs)))
+	} else {
+		atomic.AddInt64(&p.flushed, int64(len(logs)))
+	}
+
+	if oldWAL != nil {
+		if err := oldWAL.closeAndRemove(); err != nil {
+			p.logger.Error("wal cleanup failed", "error", err)
+		}
+	}
+}
+
+func (p *Pipeline) appendWithRetry(ctx context.Context, logs []storage.LogEntry) error {
+	var err error
+	for attempt := 0; attempt <= p.cfg.MaxRetries; attempt++ {
+		if err = p.store.Append(ctx, logs); err == nil {
+			return nil
+		}
+		if attempt < p.cfg.MaxRetries {
+			time.Sleep(p.cfg.RetryBackoff * time.Duration(1<<attempt))
+		}
+	}
+	return err
+}
@@ -0,0 +1,115 @@
+package ingest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/nobrainghost/heroku-logdrains-go-server/pkg/storage"
+)
+
+// walSegment is a single append-only, fsync'd WAL file. Entries written
+// to it are considered durable even if the process crashes before they
+// reach the store; replayWAL recovers them on the next startup.
+type walSegment struct {
+	mu   sync.Mutex
+	dir  string
+	path string
+	file *os.File
+}
+
+func newWALSegment(dir string) (*walSegment, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("ingest: creating wal dir %s: %w", dir, err)
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("segment-%d.wal", time.Now().UnixNano()))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("ingest: opening wal segment %s: %w", path, err)
+	}
+
+	return &walSegment{dir: dir, path: path, file: f}, nil
+}
+
+// Append writes entry to the segment and fsyncs before returning, so a
+// successful Append survives a crash.
+func (s *walSegment) Append(entry storage.LogEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("ingest: encoding wal entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(line); err != nil {
+		return fmt.Errorf("ingest: writing wal entry: %w", err)
+	}
+	return s.file.Sync()
+}
+
+// closeAndRemove closes the segment file and deletes it from disk. It
+// must only be called once every entry written to the segment has been
+// durably flushed (or dead-lettered) downstream, since the WAL no
+// longer needs to hold them at that point.
+func (s *walSegment) closeAndRemove() error {
+	s.mu.Lock()
+	path := s.path
+	s.file.Close()
+	s.mu.Unlock()
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("ingest: removing flushed wal segment %s: %w", path, err)
+	}
+	return nil
+}
+
+func (s *walSegment) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// replayWAL reads and deletes any segments left behind by a process
+// that crashed before flushing them.
+func replayWAL(dir string) ([]storage.LogEntry, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "segment-*.wal"))
+	if err != nil {
+		return nil, fmt.Errorf("ingest: listing wal segments in %s: %w", dir, err)
+	}
+
+	var entries []storage.LogEntry
+	for _, path := range matches {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("ingest: opening wal segment %s: %w", path, err)
+		}
+
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var e storage.LogEntry
+			if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+				f.Close()
+				return nil, fmt.Errorf("ingest: decoding wal entry in %s: %w", path, err)
+			}
+			entries = append(entries, e)
+		}
+		scanErr := scanner.Err()
+		f.Close()
+		if scanErr != nil {
+			return nil, fmt.Errorf("ingest: reading wal segment %s: %w", path, scanErr)
+		}
+
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("ingest: removing replayed wal segment %s: %w", path, err)
+		}
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,118 @@
+// Package metrics exposes the service's Prometheus instrumentation:
+// HTTP request counters/histograms, ingest pipeline gauges, and the
+// process/Go runtime collectors.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Metrics owns a private registry (rather than the global default) so
+// it can be constructed and wired explicitly, matching the rest of the
+// service's dependency-injection style.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	requestsTotal      *prometheus.CounterVec
+	requestDuration    *prometheus.HistogramVec
+	ingestChannelDepth prometheus.Gauge
+	batchFlushDuration prometheus.Histogram
+	batchSize          prometheus.Histogram
+	dbInsertErrors     prometheus.Counter
+}
+
+// New builds and registers all collectors.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		requestsTotal: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "logdrain_http_requests_total",
+			Help: "Total HTTP requests, by method, route, and status.",
+		}, []string{"method", "route", "status"}),
+
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "logdrain_http_request_duration_seconds",
+			Help:    "HTTP request latency, by method and route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+
+		ingestChannelDepth: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "logdrain_ingest_channel_depth",
+			Help: "Current number of buffered entries in the ingest channel.",
+		}),
+
+		batchFlushDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "logdrain_batch_flush_duration_seconds",
+			Help:    "Time taken to flush a batch of log entries to the store.",
+			Buckets: prometheus.DefBuckets,
+		}),
+
+		batchSize: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Name:    "logdrain_batch_size",
+			Help:    "Number of log entries per flushed batch.",
+			Buckets: []float64{1, 10, 50, 100, 250, 500, 1000, 5000},
+		}),
+
+		dbInsertErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "logdrain_db_insert_errors_total",
+			Help: "Batch flushes that failed even after retries.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		m.requestsTotal,
+		m.requestDuration,
+		m.ingestChannelDepth,
+		m.batchFlushDuration,
+		m.batchSize,
+		m.dbInsertErrors,
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewGoCollector(),
+	)
+
+	return m
+}
+
+// Handler serves the registry in the Prometheus exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// GinMiddleware records a requestsTotal/requestDuration observation for
+// every request, keyed by the matched route (not the raw path, to keep
+// cardinality bounded).
+func (m *Metrics) GinMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+
+		m.requestDuration.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+		m.requestsTotal.WithLabelValues(c.Request.Method, route, http.StatusText(c.Writer.Status())).Inc()
+	}
+}
+
+// ObserveFlush implements ingest.MetricsRecorder.
+func (m *Metrics) ObserveFlush(duration time.Duration, batchSize int, err error) {
+	m.batchFlushDuration.Observe(duration.Seconds())
+	m.batchSize.Observe(float64(batchSize))
+	if err != nil {
+		m.dbInsertErrors.Inc()
+	}
+}
+
+// SetIngestChannelDepth updates the ingest channel depth gauge.
+func (m *Metrics) SetIngestChannelDepth(depth int) {
+	m.ingestChannelDepth.Set(float64(depth))
+}
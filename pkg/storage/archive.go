@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// archiveStore is the cold tier: flushed batches are rolled into
+// gzipped NDJSON objects keyed by the UTC hour of the entries' own
+// timestamps, e.g.
+// "<prefix>/2026/07/26/14/<unix-nano>.ndjson.gz". It supports Append
+// (called by the Compactor) and Query (scanned linearly, since the
+// archive trades query speed for storage cost).
+type archiveStore struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+func newArchiveStore(cfg ArchiveConfig) (*archiveStore, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("storage: archive bucket must be set")
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("storage: loading AWS config: %w", err)
+	}
+
+	return &archiveStore{
+		client: s3.NewFromConfig(awsCfg),
+		bucket: cfg.Bucket,
+		prefix: cfg.Prefix,
+	}, nil
+}
+
+func (s *archiveStore) Append(ctx context.Context, entries []LogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	byHour := make(map[time.Time][]LogEntry)
+	var hours []time.Time
+	for _, e := range entries {
+		hour := e.TimeStamp.UTC().Truncate(time.Hour)
+		if _, ok := byHour[hour]; !ok {
+			hours = append(hours, hour)
+		}
+		byHour[hour] = append(byHour[hour], e)
+	}
+
+	for _, hour := range hours {
+		if err := s.putHour(ctx, hour, byHour[hour]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// putHour gzip-encodes entries as NDJSON and uploads them under the
+// object key for hour. Append splits a batch by each entry's own hour
+// before calling this, since a batch handed to Append (e.g. by the
+// Compactor, which can pop days' worth of rows at once) commonly spans
+// more than one hour bucket.
+func (s *archiveStore) putHour(ctx context.Context, hour time.Time, entries []LogEntry) error {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	enc := json.NewEncoder(gz)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("storage: encoding archive entry: %w", err)
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("storage: closing gzip writer: %w", err)
+	}
+
+	key := s.hourKey(hour)
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   bytes.NewReader(buf.Bytes()),
+	})
+	if err != nil {
+		return fmt.Errorf("storage: uploading archive object %s: %w", key, err)
+	}
+	return nil
+}
+
+// hourKey builds the object key for a batch, grouping objects under an
+// hour-granular prefix so a Query over a time range only has to list
+// the hours it overlaps.
+func (s *archiveStore) hourKey(t time.Time) string {
+	t = t.UTC()
+	return fmt.Sprintf("%s/%04d/%02d/%02d/%02d/%d.ndjson.gz",
+		s.prefix, t.Year(), t.Month(), t.Day(), t.Hour(), time.Now().UnixNano())
+}
+
+func (s *archiveStore) Query(ctx context.Context, filter LogFilter) ([]LogEntry, error) {
+	since, until := filter.Since, filter.Until
+	if since.IsZero() {
+		since = time.Now().Add(-24 * time.Hour)
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var matched []LogEntry
+	for hour := since.UTC().Truncate(time.Hour); !hour.After(until); hour = hour.Add(time.Hour) {
+		prefix := fmt.Sprintf("%s/%04d/%02d/%02d/%02d/", s.prefix, hour.Year(), hour.Month(), hour.Day(), hour.Hour())
+		objects, err := s.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket: aws.String(s.bucket),
+			Prefix: aws.String(prefix),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("storage: listing archive objects under %s: %w", prefix, err)
+		}
+
+		for _, obj := range objects.Contents {
+			entries, err := s.readObject(ctx, *obj.Key)
+			if err != nil {
+				return nil, err
+			}
+			for _, e := range entries {
+				if matchesFilter(e, filter) {
+					matched = append(matched, e)
+				}
+			}
+		}
+	}
+
+	if len(matched) > limit {
+		matched = matched[:limit]
+	}
+	return matched, nil
+}
+
+func (s *archiveStore) readObject(ctx context.Context, key string) ([]LogEntry, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: fetching archive object %s: %w", key, err)
+	}
+	defer out.Body.Close()
+
+	gz, err := gzip.NewReader(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("storage: decompressing archive object %s: %w", key, err)
+	}
+	defer gz.Close()
+
+	var entries []LogEntry
+	scanner := bufio.NewScanner(gz)
+	for scanner.Scan() {
+		var e LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("storage: decoding archive entry in %s: %w", key, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+func matchesFilter(e LogEntry, filter LogFilter) bool {
+	if filter.Source != "" && e.Source != filter.Source {
+		return false
+	}
+	if filter.Severity != nil && e.Severity != *filter.Severity {
+		return false
+	}
+	if filter.Contains != "" && !strings.Contains(strings.ToLower(e.Message), strings.ToLower(filter.Contains)) {
+		return false
+	}
+	if filter.Cursor != nil {
+		if !e.TimeStamp.Before(filter.Cursor.Timestamp) && !(e.TimeStamp.Equal(filter.Cursor.Timestamp) && e.ID < filter.Cursor.ID) {
+			return false
+		}
+	}
+	if !filter.Since.IsZero() && e.TimeStamp.Before(filter.Since) {
+		return false
+	}
+	if !filter.Until.IsZero() && !e.TimeStamp.Before(filter.Until) {
+		return false
+	}
+	return true
+}
+
+func (s *archiveStore) Close() error {
+	return nil
+}
@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// popper is implemented by hot-tier stores that support selecting
+// entries older than a cutoff and later deleting them by id, so the
+// Compactor can move them to cold storage without deleting from the hot
+// store until they're confirmed durable in the cold one.
+type popper interface {
+	selectOlderThan(ctx context.Context, cutoff time.Time) ([]LogEntry, error)
+	deleteByIDs(ctx context.Context, ids []int64) error
+}
+
+// Compactor periodically moves logs older than OlderThan from the hot
+// store into the cold archive tier.
+type Compactor struct {
+	Hot       LogStore
+	Cold      LogStore
+	OlderThan time.Duration
+	Interval  time.Duration
+}
+
+// NewCompactor builds a Compactor for the merged store returned by Open
+// when archiving is enabled. It returns an error if the hot store
+// doesn't support popping aged-out entries.
+func NewCompactor(store LogStore, olderThan, interval time.Duration) (*Compactor, error) {
+	m, ok := store.(*mergedStore)
+	if !ok {
+		return nil, fmt.Errorf("storage: compactor requires a store opened with archiving enabled")
+	}
+	if _, ok := m.hot.(popper); !ok {
+		return nil, fmt.Errorf("storage: hot store %T does not support compaction", m.hot)
+	}
+	return &Compactor{Hot: m.hot, Cold: m.cold, OlderThan: olderThan, Interval: interval}, nil
+}
+
+// Run blocks, compacting on Interval until ctx is cancelled.
+func (c *Compactor) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.compactOnce(ctx); err != nil {
+				fmt.Println("storage: compaction failed:", err)
+			}
+		}
+	}
+}
+
+// compactOnce moves one batch of aged-out entries to the cold tier. The
+// entries are only deleted from the hot store once Cold.Append has
+// confirmed they're durable there; if the archive upload fails, they're
+// left in the hot store and picked up again on the next cycle instead
+// of being silently lost.
+func (c *Compactor) compactOnce(ctx context.Context) error {
+	p := c.Hot.(popper)
+	cutoff := time.Now().Add(-c.OlderThan)
+
+	entries, err := p.selectOlderThan(ctx, cutoff)
+	if err != nil {
+		return fmt.Errorf("selecting aged entries from hot store: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	if err := c.Cold.Append(ctx, entries); err != nil {
+		return fmt.Errorf("archiving %d entries: %w", len(entries), err)
+	}
+
+	ids := make([]int64, len(entries))
+	for i, e := range entries {
+		ids[i] = e.ID
+	}
+	if err := p.deleteByIDs(ctx, ids); err != nil {
+		return fmt.Errorf("deleting %d archived entries from hot store: %w", len(entries), err)
+	}
+	return nil
+}
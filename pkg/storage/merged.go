@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"sort"
+)
+
+// mergedStore fans Query out to both the hot and cold tiers so callers
+// don't need to know which tier a given log line ended up in. Append
+// always goes to the hot tier; entries move to cold via the Compactor.
+type mergedStore struct {
+	hot  LogStore
+	cold LogStore
+}
+
+func newMergedStore(hot, cold LogStore) *mergedStore {
+	return &mergedStore{hot: hot, cold: cold}
+}
+
+func (m *mergedStore) Append(ctx context.Context, entries []LogEntry) error {
+	return m.hot.Append(ctx, entries)
+}
+
+func (m *mergedStore) Query(ctx context.Context, filter LogFilter) ([]LogEntry, error) {
+	hotEntries, err := m.hot.Query(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	if len(hotEntries) >= limit {
+		return hotEntries, nil
+	}
+
+	coldEntries, err := m.cold.Query(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := append(hotEntries, coldEntries...)
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].TimeStamp.After(merged[j].TimeStamp)
+	})
+	if len(merged) > limit {
+		merged = merged[:limit]
+	}
+	return merged, nil
+}
+
+func (m *mergedStore) Close() error {
+	if err := m.hot.Close(); err != nil {
+		return err
+	}
+	return m.cold.Close()
+}
+
+// Ping forwards to the hot store if it supports connectivity checks.
+func (m *mergedStore) Ping(ctx context.Context) error {
+	if p, ok := m.hot.(Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const logsSchema = `CREATE TABLE IF NOT EXISTS logs (
+	id SERIAL PRIMARY KEY,
+	source TEXT,
+	timestamp TIMESTAMP DEFAULT Now(),
+	message TEXT,
+	priority INTEGER,
+	facility INTEGER,
+	severity INTEGER,
+	hostname TEXT,
+	app_name TEXT,
+	proc_id TEXT,
+	msg_id TEXT,
+	structured_data TEXT
+)`
+
+// logsMigration brings a logs table created by an older deployment (just
+// id/source/timestamp/message) up to the current schema. CREATE TABLE IF
+// NOT EXISTS above is a no-op against such a table, so without this,
+// Append would fail with "column does not exist" on every upgrade.
+const logsMigration = `
+ALTER TABLE logs ADD COLUMN IF NOT EXISTS priority INTEGER;
+ALTER TABLE logs ADD COLUMN IF NOT EXISTS facility INTEGER;
+ALTER TABLE logs ADD COLUMN IF NOT EXISTS severity INTEGER;
+ALTER TABLE logs ADD COLUMN IF NOT EXISTS hostname TEXT;
+ALTER TABLE logs ADD COLUMN IF NOT EXISTS app_name TEXT;
+ALTER TABLE logs ADD COLUMN IF NOT EXISTS proc_id TEXT;
+ALTER TABLE logs ADD COLUMN IF NOT EXISTS msg_id TEXT;
+ALTER TABLE logs ADD COLUMN IF NOT EXISTS structured_data TEXT;
+`
+
+type postgresStore struct {
+	db *sql.DB
+}
+
+func newPostgresStore(connStr string) (*postgresStore, error) {
+	if connStr == "" {
+		return nil, fmt.Errorf("storage: DATABASE_URL must be set for the postgres backend")
+	}
+
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, fmt.Errorf("storage: connecting to postgres: %w", err)
+	}
+
+	if _, err := db.Exec(logsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("storage: creating logs table: %w", err)
+	}
+	if _, err := db.Exec(logsMigration); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("storage: migrating logs table: %w", err)
+	}
+
+	return &postgresStore{db: db}, nil
+}
+
+func (s *postgresStore) Append(ctx context.Context, entries []LogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	const cols = 11
+	var b strings.Builder
+	b.WriteString("INSERT INTO logs (source, timestamp, message, priority, facility, severity, hostname, app_name, proc_id, msg_id, structured_data) VALUES ")
+	args := make([]interface{}, 0, len(entries)*cols)
+
+	for i, e := range entries {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('(')
+		for j := 0; j < cols; j++ {
+			if j > 0 {
+				b.WriteByte(',')
+			}
+			fmt.Fprintf(&b, "$%d", i*cols+j+1)
+		}
+		b.WriteByte(')')
+		args = append(args, e.Source, e.TimeStamp, e.Message, e.Priority, e.Facility,
+			e.Severity, e.Hostname, e.AppName, e.ProcID, e.MsgID, e.StructuredData)
+	}
+
+	_, err := s.db.ExecContext(ctx, b.String(), args...)
+	return err
+}
+
+func (s *postgresStore) Query(ctx context.Context, filter LogFilter) ([]LogEntry, error) {
+	query := `SELECT id, source, timestamp, message, priority, facility, severity,
+		hostname, app_name, proc_id, msg_id, structured_data FROM logs WHERE 1=1`
+	var args []interface{}
+
+	if filter.Source != "" {
+		args = append(args, filter.Source)
+		query += fmt.Sprintf(" AND source = $%d", len(args))
+	}
+	if filter.Severity != nil {
+		args = append(args, *filter.Severity)
+		query += fmt.Sprintf(" AND severity = $%d", len(args))
+	}
+	if !filter.Since.IsZero() {
+		args = append(args, filter.Since)
+		query += fmt.Sprintf(" AND timestamp >= $%d", len(args))
+	}
+	if !filter.Until.IsZero() {
+		args = append(args, filter.Until)
+		query += fmt.Sprintf(" AND timestamp < $%d", len(args))
+	}
+	if filter.Contains != "" {
+		args = append(args, "%"+filter.Contains+"%")
+		query += fmt.Sprintf(" AND message ILIKE $%d", len(args))
+	}
+	if filter.Cursor != nil {
+		args = append(args, filter.Cursor.Timestamp, filter.Cursor.ID)
+		query += fmt.Sprintf(" AND (timestamp < $%d OR (timestamp = $%d AND id < $%d))", len(args)-1, len(args)-1, len(args))
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY timestamp DESC, id DESC LIMIT $%d", len(args))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLogEntries(rows)
+}
+
+func (s *postgresStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *postgresStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// selectOlderThan returns entries older than cutoff without deleting
+// them. The Compactor only deletes them (via deleteByIDs) once they're
+// confirmed durable in the cold tier, so a failed archive upload leaves
+// them here to be retried on the next compaction cycle instead of lost.
+func (s *postgresStore) selectOlderThan(ctx context.Context, cutoff time.Time) ([]LogEntry, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT id, source, timestamp, message, priority, facility, severity,
+		hostname, app_name, proc_id, msg_id, structured_data FROM logs WHERE timestamp < $1`, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLogEntries(rows)
+}
+
+// deleteByIDs removes rows by id, called by the Compactor once it has
+// confirmed those rows were archived successfully.
+func (s *postgresStore) deleteByIDs(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := s.db.ExecContext(ctx, `DELETE FROM logs WHERE id = ANY($1)`, pq.Array(ids))
+	return err
+}
+
+type rowScanner interface {
+	Next() bool
+	Scan(dest ...interface{}) error
+}
+
+func scanLogEntries(rows rowScanner) ([]LogEntry, error) {
+	var entries []LogEntry
+	for rows.Next() {
+		var e LogEntry
+		if err := rows.Scan(&e.ID, &e.Source, &e.TimeStamp, &e.Message, &e.Priority, &e.Facility,
+			&e.Severity, &e.Hostname, &e.AppName, &e.ProcID, &e.MsgID, &e.StructuredData); err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
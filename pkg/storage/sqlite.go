@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+const sqliteLogsSchema = `CREATE TABLE IF NOT EXISTS logs (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	source TEXT,
+	timestamp DATETIME DEFAULT CURRENT_TIMESTAMP,
+	message TEXT,
+	priority INTEGER,
+	facility INTEGER,
+	severity INTEGER,
+	hostname TEXT,
+	app_name TEXT,
+	proc_id TEXT,
+	msg_id TEXT,
+	structured_data TEXT
+)`
+
+// sqliteStore is intended for local development, where spinning up a
+// Postgres instance is overkill.
+type sqliteStore struct {
+	db *sql.DB
+}
+
+func newSQLiteStore(path string) (*sqliteStore, error) {
+	if path == "" {
+		path = "logdrain.db"
+	}
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("storage: opening sqlite db %q: %w", path, err)
+	}
+
+	if _, err := db.Exec(sqliteLogsSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("storage: creating logs table: %w", err)
+	}
+
+	return &sqliteStore{db: db}, nil
+}
+
+func (s *sqliteStore) Append(ctx context.Context, entries []LogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	const cols = 11
+	var b strings.Builder
+	b.WriteString("INSERT INTO logs (source, timestamp, message, priority, facility, severity, hostname, app_name, proc_id, msg_id, structured_data) VALUES ")
+	args := make([]interface{}, 0, len(entries)*cols)
+	placeholders := "(" + strings.TrimSuffix(strings.Repeat("?,", cols), ",") + ")"
+
+	for i, e := range entries {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(placeholders)
+		args = append(args, e.Source, e.TimeStamp, e.Message, e.Priority, e.Facility,
+			e.Severity, e.Hostname, e.AppName, e.ProcID, e.MsgID, e.StructuredData)
+	}
+
+	_, err := s.db.ExecContext(ctx, b.String(), args...)
+	return err
+}
+
+func (s *sqliteStore) Query(ctx context.Context, filter LogFilter) ([]LogEntry, error) {
+	query := `SELECT id, source, timestamp, message, priority, facility, severity,
+		hostname, app_name, proc_id, msg_id, structured_data FROM logs WHERE 1=1`
+	var args []interface{}
+
+	if filter.Source != "" {
+		query += " AND source = ?"
+		args = append(args, filter.Source)
+	}
+	if filter.Severity != nil {
+		query += " AND severity = ?"
+		args = append(args, *filter.Severity)
+	}
+	if !filter.Since.IsZero() {
+		query += " AND timestamp >= ?"
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		query += " AND timestamp < ?"
+		args = append(args, filter.Until)
+	}
+	if filter.Contains != "" {
+		query += " AND message LIKE ?"
+		args = append(args, "%"+filter.Contains+"%")
+	}
+	if filter.Cursor != nil {
+		query += " AND (timestamp < ? OR (timestamp = ? AND id < ?))"
+		args = append(args, filter.Cursor.Timestamp, filter.Cursor.Timestamp, filter.Cursor.ID)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	query += " ORDER BY timestamp DESC, id DESC LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanLogEntries(rows)
+}
+
+func (s *sqliteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *sqliteStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
@@ -0,0 +1,119 @@
+// Package storage abstracts the persistence layer for drained log
+// entries behind the LogStore interface so the ingest pipeline doesn't
+// depend on a concrete database driver.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LogEntry is the structured representation of a single drained log
+// line, decoded from its RFC5424 syslog form.
+type LogEntry struct {
+	ID             int64
+	Source         string
+	TimeStamp      time.Time
+	Message        string
+	Priority       int
+	Facility       int
+	Severity       int
+	Hostname       string
+	AppName        string
+	ProcID         string
+	MsgID          string
+	StructuredData string
+}
+
+// Cursor is a keyset pagination bookmark: the (timestamp, id) of the
+// last entry seen, so the next page can pick up after it in O(log n)
+// instead of paying for a large OFFSET.
+type Cursor struct {
+	Timestamp time.Time
+	ID        int64
+}
+
+// LogFilter narrows a Query to a subset of stored entries.
+type LogFilter struct {
+	Source   string
+	Severity *int
+	Since    time.Time
+	Until    time.Time
+	Contains string
+	Cursor   *Cursor
+	Limit    int
+}
+
+// LogStore persists and retrieves LogEntry values. Implementations must
+// be safe for concurrent use.
+type LogStore interface {
+	Append(ctx context.Context, entries []LogEntry) error
+	Query(ctx context.Context, filter LogFilter) ([]LogEntry, error)
+	Close() error
+}
+
+// Pinger is implemented by stores backed by a reachable database, so
+// the /readyz probe can check connectivity. Archive-only stores don't
+// implement it.
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Backend selects which LogStore implementation Open constructs.
+type Backend string
+
+const (
+	BackendPostgres Backend = "postgres"
+	BackendSQLite   Backend = "sqlite"
+)
+
+// ArchiveConfig configures the optional cold-storage tier that the
+// compactor rolls aged-out hot entries into.
+type ArchiveConfig struct {
+	Enabled         bool
+	Bucket          string
+	Prefix          string
+	OlderThan       time.Duration
+	CompactInterval time.Duration
+}
+
+// Config selects and configures a LogStore.
+type Config struct {
+	Backend     Backend
+	DatabaseURL string // used when Backend == BackendPostgres
+	SQLitePath  string // used when Backend == BackendSQLite
+	Archive     ArchiveConfig
+}
+
+// Open constructs the hot LogStore selected by cfg.Backend. If
+// cfg.Archive.Enabled, the returned store transparently fans Query out
+// to the archive tier as well and a Compactor should be started
+// alongside it (see NewCompactor).
+func Open(cfg Config) (LogStore, error) {
+	var hot LogStore
+	var err error
+
+	switch cfg.Backend {
+	case BackendSQLite:
+		hot, err = newSQLiteStore(cfg.SQLitePath)
+	case BackendPostgres, "":
+		hot, err = newPostgresStore(cfg.DatabaseURL)
+	default:
+		return nil, fmt.Errorf("storage: unknown backend %q", cfg.Backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.Archive.Enabled {
+		return hot, nil
+	}
+
+	cold, err := newArchiveStore(cfg.Archive)
+	if err != nil {
+		hot.Close()
+		return nil, err
+	}
+	return newMergedStore(hot, cold), nil
+}